@@ -0,0 +1,214 @@
+// Package deltasync implements the classic rsync rolling-checksum algorithm
+// as a native binary delta, so a Mismatch file can be updated in place over
+// a plain byte stream instead of being copied whole or handed to an
+// external rsync binary.
+package deltasync
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// op is one reconstruction instruction: either copy an existing block from
+// the destination's old file, or literal bytes read directly from source.
+type op struct {
+	copyBlock int // -1 for a literal op
+	literal   []byte
+}
+
+// Patch updates dst in place to match src, transferring only the bytes that
+// changed. It follows the classic rsync algorithm:
+//
+//  1. dst's existing contents are split into fixed-size blocks and signed
+//     with a weak rolling checksum plus a strong hash.
+//  2. src is scanned byte-by-byte with a sliding window; a weak-sum hit is
+//     verified against the strong hash, and on a verified match the window
+//     jumps forward by a full block instead of one byte.
+//  3. The resulting copy-block/literal instructions are replayed into a
+//     temp file, which is atomically renamed over dst.
+func Patch(src io.ReaderAt, dst *os.File) error {
+	if _, err := dst.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sigs, err := signatures(dst)
+	if err != nil {
+		return fmt.Errorf("deltasync: signing destination: %w", err)
+	}
+
+	ops, err := diffAgainstSignatures(src, sigs)
+	if err != nil {
+		return fmt.Errorf("deltasync: diffing source: %w", err)
+	}
+
+	return reconstruct(dst, ops)
+}
+
+// diffAgainstSignatures scans src against sigs, emitting a copy-block
+// instruction for every verified match and literal runs everywhere else.
+// The window's weak sum is maintained incrementally with roll instead of
+// being recomputed from scratch at every offset, so the byte-by-byte slide
+// is O(1) per byte as the rsync algorithm intends.
+func diffAgainstSignatures(src io.ReaderAt, sigs []blockSig) ([]op, error) {
+	byWeak := make(map[uint32][]blockSig, len(sigs))
+	for _, s := range sigs {
+		byWeak[s.weak] = append(byWeak[s.weak], s)
+	}
+
+	var ops []op
+	var literal []byte
+	flushLiteral := func() {
+		if len(literal) > 0 {
+			ops = append(ops, op{copyBlock: -1, literal: literal})
+			literal = nil
+		}
+	}
+
+	window := make([]byte, 0, blockSize)
+	var roll *rollingChecksum
+	var offset int64
+	reader := newSrcReader(src)
+
+	for {
+		// Fill the window up to blockSize bytes, byte by byte, so a miss
+		// can resume scanning from wherever the window currently ends.
+		for len(window) < blockSize {
+			c, ok, err := reader.byteAt(offset + int64(len(window)))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			window = append(window, c)
+		}
+		if len(window) == 0 {
+			break
+		}
+		if roll == nil || int(roll.len) != len(window) {
+			roll = newRollingChecksum(window)
+		}
+
+		if cand, ok := matchBlock(window, roll.sum(), byWeak); ok {
+			flushLiteral()
+			ops = append(ops, op{copyBlock: cand})
+			offset += int64(len(window))
+			window = window[:0]
+			roll = nil
+			continue
+		}
+
+		// No match for this window; emit its first byte as a literal and
+		// slide forward by one, the classic rsync byte-by-byte advance,
+		// rolling the weak sum forward instead of re-summing the window.
+		out := window[0]
+		literal = append(literal, out)
+		c, ok, err := reader.byteAt(offset + int64(len(window)))
+		if err != nil {
+			return nil, err
+		}
+		offset++
+		if ok {
+			roll.roll(out, c)
+			window = append(window[1:], c)
+		} else {
+			// The window is shrinking toward EOF, so its length no longer
+			// matches roll's fixed len; it's rebuilt on the next iteration.
+			window = window[1:]
+		}
+	}
+	flushLiteral()
+	return ops, nil
+}
+
+// matchBlock checks window's precomputed weak sum against candidates and
+// verifies with the strong hash, returning the matching block's index.
+func matchBlock(window []byte, weak uint32, byWeak map[uint32][]blockSig) (int, bool) {
+	candidates, ok := byWeak[weak]
+	if !ok {
+		return 0, false
+	}
+	strong := strongSum(window)
+	for _, c := range candidates {
+		if c.strong == strong {
+			return c.index, true
+		}
+	}
+	return 0, false
+}
+
+// srcReaderAhead is how much of src is buffered per underlying ReadAt call,
+// so scanning byte-by-byte over a slow transport (SFTP) costs one round
+// trip per buffer instead of one per byte.
+const srcReaderAhead = 256 * blockSize
+
+// srcReader buffers sequential ReadAt calls into src so diffAgainstSignatures
+// can read one byte at a time without a round trip per byte.
+type srcReader struct {
+	src  io.ReaderAt
+	buf  []byte
+	base int64 // absolute offset of buf[0]
+	n    int   // valid bytes in buf, starting at base
+}
+
+func newSrcReader(src io.ReaderAt) *srcReader {
+	return &srcReader{src: src, buf: make([]byte, srcReaderAhead), base: -1}
+}
+
+// byteAt returns the byte at absolute offset at, refilling the buffer from
+// src when at falls outside it.
+func (r *srcReader) byteAt(at int64) (byte, bool, error) {
+	if r.base < 0 || at < r.base || at >= r.base+int64(r.n) {
+		n, err := r.src.ReadAt(r.buf, at)
+		if err != nil && err != io.EOF {
+			return 0, false, err
+		}
+		r.base, r.n = at, n
+		if n == 0 {
+			return 0, false, nil
+		}
+	}
+	return r.buf[at-r.base], true, nil
+}
+
+// reconstruct replays ops into a temp file alongside dst, reading
+// copy-block instructions back out of dst's own prior contents, then
+// atomically renames the temp file over dst.
+func reconstruct(dst *os.File, ops []op) error {
+	dir := filepath.Dir(dst.Name())
+	tmp, err := os.CreateTemp(dir, filepath.Base(dst.Name())+".deltasync-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		tmp.Close()
+		os.Remove(tmpPath)
+	}()
+
+	for _, o := range ops {
+		if o.copyBlock < 0 {
+			if _, err := tmp.Write(o.literal); err != nil {
+				return err
+			}
+			continue
+		}
+		buf := make([]byte, blockSize)
+		n, err := dst.ReadAt(buf, int64(o.copyBlock)*blockSize)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if _, err := tmp.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dst.Name())
+}
@@ -0,0 +1,45 @@
+package deltasync_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"titanic_app/deltasync"
+)
+
+func TestPatchReconstructsSource(t *testing.T) {
+	tmp := t.TempDir()
+	dstPath := filepath.Join(tmp, "dst.bin")
+
+	old := bytes.Repeat([]byte("A"), 4096*3)
+	if err := os.WriteFile(dstPath, old, 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// New content reuses blocks 0 and 2 unchanged, rewrites block 1, and
+	// appends a short tail shorter than a full block.
+	newContent := append([]byte{}, old[:4096]...)
+	newContent = append(newContent, bytes.Repeat([]byte("B"), 4096)...)
+	newContent = append(newContent, old[4096*2:4096*3]...)
+	newContent = append(newContent, []byte("tail")...)
+
+	dst, err := os.OpenFile(dstPath, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer dst.Close()
+
+	if err := deltasync.Patch(bytes.NewReader(newContent), dst); err != nil {
+		t.Fatalf("Patch returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(dstPath)
+	if err != nil {
+		t.Fatalf("readfile failed: %v", err)
+	}
+	if !bytes.Equal(got, newContent) {
+		t.Fatalf("reconstructed file does not match source: got %d bytes, want %d", len(got), len(newContent))
+	}
+}
@@ -0,0 +1,47 @@
+package deltasync
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestDiffAgainstSignaturesReusesUnchangedBlocks is a white-box complement
+// to TestPatchReconstructsSource: reconstructed-content equality alone
+// can't tell a real delta from one that degenerated to all-literal ops, so
+// this asserts directly on the ops diffAgainstSignatures emits.
+func TestDiffAgainstSignaturesReusesUnchangedBlocks(t *testing.T) {
+	blockA := bytes.Repeat([]byte("A"), blockSize)
+	blockB := bytes.Repeat([]byte("B"), blockSize)
+	blockC := bytes.Repeat([]byte("C"), blockSize)
+	old := append(append(append([]byte{}, blockA...), blockB...), blockC...)
+
+	sigs, err := signatures(bytes.NewReader(old))
+	if err != nil {
+		t.Fatalf("signatures returned error: %v", err)
+	}
+
+	// Only the middle block changes; the first and last should still be
+	// found and emitted as copy-block ops, not re-sent as literals.
+	changed := bytes.Repeat([]byte("X"), blockSize)
+	newContent := append(append(append([]byte{}, blockA...), changed...), blockC...)
+
+	ops, err := diffAgainstSignatures(bytes.NewReader(newContent), sigs)
+	if err != nil {
+		t.Fatalf("diffAgainstSignatures returned error: %v", err)
+	}
+
+	var copyBlocks, literalBytes int
+	for _, o := range ops {
+		if o.copyBlock < 0 {
+			literalBytes += len(o.literal)
+		} else {
+			copyBlocks++
+		}
+	}
+	if copyBlocks < 2 {
+		t.Fatalf("expected the 2 unchanged blocks to be matched as copy-block ops, got %d copy-block ops and %d literal bytes", copyBlocks, literalBytes)
+	}
+	if literalBytes >= len(newContent)-blockSize {
+		t.Fatalf("expected literal bytes to stay near one block, got %d of %d total bytes", literalBytes, len(newContent))
+	}
+}
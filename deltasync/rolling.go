@@ -0,0 +1,51 @@
+package deltasync
+
+// rollingChecksum implements the rsync rolling weak checksum: an
+// Adler-32-shaped pair of running sums, but without Adler-32's additive
+// seed, since that seed isn't part of a's recurrence as the window rolls
+// and would otherwise drift the sum by a constant on every roll. Adding a
+// byte to the window and removing the oldest one is O(1), so the source can
+// slide its window forward one byte at a time without re-summing the whole
+// block on every miss.
+type rollingChecksum struct {
+	a, b uint32
+	len  uint32
+}
+
+// const modulus matches adler32's, keeping the same overflow behaviour
+// without needing to re-derive it from hash/adler32 (which doesn't expose
+// roll support).
+const modulus = 65521
+
+// newRollingChecksum seeds a, b from an initial window.
+func newRollingChecksum(window []byte) *rollingChecksum {
+	r := &rollingChecksum{len: uint32(len(window))}
+	for i, c := range window {
+		r.a += uint32(c)
+		r.b += (r.len - uint32(i)) * uint32(c)
+	}
+	r.a %= modulus
+	r.b %= modulus
+	return r
+}
+
+// sum returns the current weak checksum, combining a and b the same way
+// adler32 does.
+func (r *rollingChecksum) sum() uint32 {
+	return r.a | (r.b << 16)
+}
+
+// roll slides the window forward by one byte: out leaves, in enters.
+func (r *rollingChecksum) roll(out, in byte) {
+	// Adding modulus before each subtraction keeps the uint32 arithmetic
+	// from wrapping below zero, which would skew the sum by 2^32 mod modulus.
+	r.a = (r.a + modulus - uint32(out) + uint32(in)) % modulus
+	r.b = (r.b + modulus - (r.len*uint32(out))%modulus + r.a) % modulus
+}
+
+// weakSum computes the rolling checksum of a single, non-sliding window —
+// used when signing the destination's existing blocks, where no rolling is
+// needed.
+func weakSum(window []byte) uint32 {
+	return newRollingChecksum(window).sum()
+}
@@ -0,0 +1,55 @@
+package deltasync
+
+import (
+	"io"
+
+	"github.com/zeebo/blake3"
+)
+
+// blockSize is the fixed block size the classic rsync algorithm splits
+// files into before computing per-block signatures.
+const blockSize = 4096
+
+// strongSize is how many bytes of the BLAKE3 digest are kept per block —
+// enough to make a weak-sum collision practically impossible without paying
+// for a full 256-bit hash on every block.
+const strongSize = 16
+
+// blockSig is the rolling-checksum signature of one block of an existing
+// file: a fast weak sum for finding candidate matches, backed by a strong
+// hash that rules out weak-sum collisions.
+type blockSig struct {
+	index  int
+	weak   uint32
+	strong [strongSize]byte
+}
+
+// signatures splits r into fixed-size blocks and computes a blockSig for
+// each. This is the list the destination sends to the source in the classic
+// rsync algorithm, computed once over the file already on disk.
+func signatures(r io.Reader) ([]blockSig, error) {
+	var sigs []blockSig
+	buf := make([]byte, blockSize)
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			block := buf[:n]
+			sigs = append(sigs, blockSig{index: i, weak: weakSum(block), strong: strongSum(block)})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sigs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// strongSum returns the truncated BLAKE3 digest used to confirm a weak-sum
+// match.
+func strongSum(b []byte) [strongSize]byte {
+	full := blake3.Sum256(b)
+	var out [strongSize]byte
+	copy(out[:], full[:strongSize])
+	return out
+}
@@ -0,0 +1,257 @@
+package diff
+
+import (
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cacheRecord is a persisted cache entry, keyed by the cleaned absolute path
+// it was computed for. A file's record holds the stat fields ListLocal
+// checks for staleness and its content hash. A directory's record holds the
+// same stat fields for the directory inode itself (BuildKit calls this the
+// header) plus its recursive content digest and the sorted names of its
+// immediate children, so an unchanged directory's child list can be reused
+// without a fresh ReadDir.
+type cacheRecord struct {
+	Size     int64
+	ModTime  int64
+	Mode     uint32
+	Algo     string
+	Hash     string // file content hash, or a directory's recursive digest
+	IsDir    bool
+	Children []string
+}
+
+// dirUnchanged reports whether rec and other describe the same directory
+// state, so hashDir can skip writing (and marking the cache dirty) when a
+// refresh recomputes an identical record.
+func (rec *cacheRecord) dirUnchanged(other *cacheRecord) bool {
+	if rec.Size != other.Size || rec.ModTime != other.ModTime || rec.Mode != other.Mode ||
+		rec.Algo != other.Algo || rec.Hash != other.Hash || len(rec.Children) != len(other.Children) {
+		return false
+	}
+	for i, name := range rec.Children {
+		if other.Children[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheFileEntry is the on-disk (gob) representation of a single cacheRecord.
+// The tree itself is rebuilt from a flat list of entries on load, since the
+// radixNode's unexported fields can't be gob-encoded directly.
+type cacheFileEntry struct {
+	Path   string
+	Record cacheRecord
+}
+
+// CacheContext is a persistent, content-addressable hash cache that lets
+// ListLocal skip re-hashing files whose size, mtime, and mode are unchanged
+// since the last run. Entries live in a radix tree keyed by cleaned absolute
+// path and are flushed to disk as gob-encoded entries.
+type CacheContext struct {
+	path  string
+	tree  *radixNode
+	dirty bool
+}
+
+// NewCacheContext loads the cache persisted at path, if one exists, or
+// returns an empty cache ready to be populated and saved later.
+func NewCacheContext(path string) (*CacheContext, error) {
+	c := &CacheContext{path: path, tree: newRadixNode("")}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []cacheFileEntry
+	if err := gob.NewDecoder(f).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode cache %s: %w", path, err)
+	}
+	for _, e := range entries {
+		rec := e.Record
+		c.tree.insert(e.Path, &rec)
+	}
+	return c, nil
+}
+
+// CachePath returns the default on-disk cache location for the directory
+// pair identified by pairID, rooted at ~/.cache/titanic.
+func CachePath(pairID string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "titanic", pairID+".cache"), nil
+}
+
+// Save persists the cache to its configured path if it has changed since it
+// was loaded, writing to a temp file first and renaming into place.
+func (c *CacheContext) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	var entries []cacheFileEntry
+	c.tree.walk("", func(key string, rec *cacheRecord) {
+		entries = append(entries, cacheFileEntry{Path: key, Record: *rec})
+	})
+
+	tmp := c.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(entries); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// ListLocal walks dir and returns hashes for all files, computed with h and
+// reusing cached hashes for files whose size, modification time, mode, and
+// hash algorithm haven't changed since the last run. It also caches each
+// directory's recursive content digest (see cacheRecord), which lets an
+// unchanged directory's ReadDir be skipped on the next call in favor of its
+// previously recorded child list.
+func (c *CacheContext) ListLocal(dir string, h Hasher) ([]FileHash, error) {
+	dir = strings.TrimRight(dir, string(os.PathSeparator))
+	var results []FileHash
+	if _, err := c.hashDir(dir, dir, h, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// hashDir hashes every file under dir, appending root-relative FileHash
+// entries to results, and returns dir's recursive content digest.
+//
+// Before calling ReadDir it checks dir's own header (size, mtime, mode)
+// against the cached directory record: if that's unchanged, the entry names
+// from last time are reused instead of re-reading the directory — the
+// directory's mtime only moves when an entry is added, removed, or renamed,
+// so this is safe to skip. It's not safe to skip visiting each of those
+// entries, though: a file's mtime can change without touching its parent
+// directory's, so every child still goes through the per-file cache check
+// (or, for a subdirectory, this same rule applied recursively).
+func (c *CacheContext) hashDir(dir, root string, h Hasher, results *[]FileHash) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	abs = filepath.Clean(abs)
+	size, modNs, mode := info.Size(), info.ModTime().UnixNano(), uint32(info.Mode())
+
+	var names []string
+	if rec, ok := c.tree.get(abs); ok && rec.IsDir && rec.Size == size && rec.ModTime == modNs && rec.Mode == mode {
+		names = rec.Children
+	} else {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		names = make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.Name()
+		}
+		sort.Strings(names)
+	}
+
+	sum := h.New()
+	for _, name := range names {
+		childPath := filepath.Join(dir, name)
+		childInfo, err := os.Lstat(childPath)
+		if err != nil {
+			return "", err
+		}
+		if childInfo.IsDir() {
+			digest, err := c.hashDir(childPath, root, h, results)
+			if err != nil {
+				return "", err
+			}
+			io.WriteString(sum, name+"/")
+			io.WriteString(sum, digest)
+			continue
+		}
+		hash, err := c.hashFileCached(childPath, childInfo, h)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(root, childPath)
+		if err != nil {
+			return "", err
+		}
+		*results = append(*results, FileHash{Path: rel, Hash: hash})
+		io.WriteString(sum, name)
+		io.WriteString(sum, hash)
+	}
+	digest := hex.EncodeToString(sum.Sum(nil))
+
+	rec := &cacheRecord{IsDir: true, Size: size, ModTime: modNs, Mode: mode, Algo: h.Name(), Hash: digest, Children: names}
+	if existing, ok := c.tree.get(abs); !ok || !existing.dirUnchanged(rec) {
+		c.tree.insert(abs, rec)
+		c.dirty = true
+	}
+	return digest, nil
+}
+
+// hashFileCached returns path's hash, computed with h, reusing the cached
+// record if path's size, mtime, mode, and hash algorithm are unchanged.
+func (c *CacheContext) hashFileCached(path string, info os.FileInfo, h Hasher) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	abs = filepath.Clean(abs)
+	size, modNs, mode := info.Size(), info.ModTime().UnixNano(), uint32(info.Mode())
+
+	if rec, ok := c.tree.get(abs); ok && !rec.IsDir && rec.Size == size && rec.ModTime == modNs && rec.Mode == mode && rec.Algo == h.Name() {
+		return rec.Hash, nil
+	}
+	hash, err := hashFile(path, h)
+	if err != nil {
+		return "", err
+	}
+	c.tree.insert(abs, &cacheRecord{Size: size, ModTime: modNs, Mode: mode, Algo: h.Name(), Hash: hash})
+	c.dirty = true
+	return hash, nil
+}
+
+// hashFile computes the hash of the file at path using h.
+func hashFile(path string, h Hasher) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	sum := h.New()
+	if _, err := io.Copy(sum, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,75 @@
+package diff_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"titanic_app/diff"
+)
+
+func TestCacheContextReusesUnchangedHashes(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "a.txt")
+	if err := os.WriteFile(path, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	cachePath := filepath.Join(tmp, "cache", "pair.cache")
+	c, err := diff.NewCacheContext(cachePath)
+	if err != nil {
+		t.Fatalf("NewCacheContext returned error: %v", err)
+	}
+
+	first, err := c.ListLocal(tmp, diff.MD5)
+	if err != nil {
+		t.Fatalf("ListLocal returned error: %v", err)
+	}
+	if len(first) != 1 || first[0].Hash == "" {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+
+	// Mutate the file on disk without changing its mtime; if the cache is
+	// consulted by (size, mtime, mode) the stale hash will be returned.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("hello worl!"), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	second, err := c.ListLocal(tmp, diff.MD5)
+	if err != nil {
+		t.Fatalf("second ListLocal returned error: %v", err)
+	}
+	if second[0].Hash != first[0].Hash {
+		t.Errorf("expected cached hash %s to be reused, got %s", first[0].Hash, second[0].Hash)
+	}
+
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reloaded, err := diff.NewCacheContext(cachePath)
+	if err != nil {
+		t.Fatalf("reloading cache returned error: %v", err)
+	}
+	// Advance mtime so the change above is actually detected once the cache
+	// is consulted from a freshly loaded tree.
+	future := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+	third, err := reloaded.ListLocal(tmp, diff.MD5)
+	if err != nil {
+		t.Fatalf("ListLocal on reloaded cache returned error: %v", err)
+	}
+	if third[0].Hash == first[0].Hash {
+		t.Errorf("expected hash to change after mtime bump, still got %s", third[0].Hash)
+	}
+}
@@ -2,21 +2,17 @@
 package diff
 
 import (
-	"bufio"
-	"bytes"
-	"crypto/md5"
 	"encoding/hex"
-	"fmt"
 	"io"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 )
 
-// FileHash holds a file path and its MD5 hash.
+// FileHash holds a file path and its hash, computed with whichever Hasher
+// was passed to ListLocal.
 type FileHash struct {
 	Path string
 	Hash string
@@ -44,11 +40,9 @@ type Diff struct {
 	Status  DiffStatus
 }
 
-// ExecCommand is the function used to invoke external commands (e.g., ssh). Can be overridden in tests.
-var ExecCommand = exec.Command
-
-// ListLocal walks the given directory and returns MD5 hashes for all files.
-func ListLocal(dir string) ([]FileHash, error) {
+// ListLocal walks the given directory and returns hashes for all files,
+// computed with h.
+func ListLocal(dir string, h Hasher) ([]FileHash, error) {
 	var results []FileHash
 	dir = strings.TrimRight(dir, string(os.PathSeparator))
 	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
@@ -67,11 +61,11 @@ func ListLocal(dir string) ([]FileHash, error) {
 			return err
 		}
 		defer f.Close()
-		h := md5.New()
-		if _, err := io.Copy(h, f); err != nil {
+		sum := h.New()
+		if _, err := io.Copy(sum, f); err != nil {
 			return err
 		}
-		hash := hex.EncodeToString(h.Sum(nil))
+		hash := hex.EncodeToString(sum.Sum(nil))
 		results = append(results, FileHash{Path: rel, Hash: hash})
 		return nil
 	})
@@ -81,41 +75,6 @@ func ListLocal(dir string) ([]FileHash, error) {
 	return results, nil
 }
 
-// ListRemote connects to a remote host via SSH, runs md5sum, and parses the results.
-// The addr should be in the form "host:/absolute/path".
-func ListRemote(addr string) ([]FileHash, error) {
-	parts := strings.SplitN(addr, ":", 2)
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid remote address %s", addr)
-	}
-	host, base := parts[0], parts[1]
-	base = strings.TrimRight(base, "/")
-	cmd := ExecCommand("ssh", host, fmt.Sprintf("cd %s && find . -type f -exec md5sum {} +", base))
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("ssh error: %w", err)
-	}
-
-	var results []FileHash
-	s := bufio.NewScanner(&out)
-	for s.Scan() {
-		fields := strings.Fields(s.Text())
-		if len(fields) < 2 {
-			continue
-		}
-		h := fields[0]
-		file := fields[1]
-		rel := strings.TrimPrefix(file, "./")
-		results = append(results, FileHash{Path: rel, Hash: h})
-	}
-	if err := s.Err(); err != nil {
-		return nil, err
-	}
-	return results, nil
-}
-
 // ComputeDiff compares two file-hash lists and returns a sorted slice of Diff.
 func ComputeDiff(srcList, dstList []FileHash) []Diff {
 	srcMap := make(map[string]string, len(srcList))
@@ -34,7 +34,7 @@ func TestListLocal(t *testing.T) {
 		}
 	}
 
-	list, err := diff.ListLocal(tmp)
+	list, err := diff.ListLocal(tmp, diff.MD5)
 	if err != nil {
 		t.Fatalf("ListLocal returned error: %v", err)
 	}
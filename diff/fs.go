@@ -0,0 +1,241 @@
+package diff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"titanic_app/transport"
+)
+
+// FS is a minimal virtual filesystem abstraction: walk, read, stat, and
+// write files without the caller needing to know whether the backend is
+// local disk, SFTP, or an object store. Backends register themselves by URL
+// scheme via RegisterFS, the same way database/sql drivers register
+// themselves, so adding one doesn't require touching ListFS or the TUI.
+type FS interface {
+	// Walk calls fn for every regular file under root, with paths relative to root.
+	Walk(root string, fn func(path string, info transport.FileInfo) error) error
+	// Open opens path for reading.
+	Open(path string) (io.ReadCloser, error)
+	// Stat returns file info for path.
+	Stat(path string) (transport.FileInfo, error)
+	// MkdirAll creates path and any missing parents.
+	MkdirAll(path string) error
+	// Create creates (or truncates) path for writing.
+	Create(path string) (io.WriteCloser, error)
+}
+
+// FSFactory constructs an FS from the address that followed a registered
+// scheme's "scheme://" prefix.
+type FSFactory func(addr string) (FS, error)
+
+var fsRegistry = make(map[string]FSFactory)
+
+// RegisterFS registers factory to handle "scheme://..." addresses. Call
+// from an init() in the package providing the backend.
+func RegisterFS(scheme string, factory FSFactory) {
+	fsRegistry[scheme] = factory
+}
+
+func init() {
+	RegisterFS("file", func(addr string) (FS, error) {
+		return fsAdapter{transport.NewLocal(addr)}, nil
+	})
+	RegisterFS("sftp", func(addr string) (FS, error) {
+		host, base, ok := splitHostPath(addr)
+		if !ok {
+			return nil, fmt.Errorf("sftp address %q must be host:/path", addr)
+		}
+		remote, err := transport.DialSFTP(host, base)
+		if err != nil {
+			return nil, err
+		}
+		return fsAdapter{remote}, nil
+	})
+}
+
+// OpenFS resolves addr to a registered FS. "scheme://..." addresses are
+// dispatched to whichever backend registered that scheme (s3, gs, webdav,
+// ...); a legacy "host:/path" address (no "://") dials SFTP directly for
+// backward compatibility with existing DirectoryPair configs; anything else
+// is a local path.
+func OpenFS(addr string) (FS, error) {
+	if idx := strings.Index(addr, "://"); idx != -1 {
+		scheme, rest := addr[:idx], addr[idx+len("://"):]
+		factory, ok := fsRegistry[scheme]
+		if !ok {
+			return nil, fmt.Errorf("no FS backend registered for scheme %q", scheme)
+		}
+		return factory(rest)
+	}
+	if host, base, ok := splitHostPath(addr); ok {
+		remote, err := transport.DialSFTP(host, base)
+		if err != nil {
+			return nil, err
+		}
+		return fsAdapter{remote}, nil
+	}
+	return fsAdapter{transport.NewLocal(addr)}, nil
+}
+
+// IsSFTP reports whether addr resolves to an SFTP backend — either the
+// legacy "host:/path" form or an explicit "sftp://" scheme. Callers that
+// need an io.ReaderAt over the source, such as patchFile's deltasync
+// transfer, can only rely on that from the SFTP transport, not from a
+// scheme address in general (s3://, gs://, webdav://, file:// all contain a
+// ":" too, so checking for one isn't enough).
+func IsSFTP(addr string) bool {
+	if idx := strings.Index(addr, "://"); idx != -1 {
+		return addr[:idx] == "sftp"
+	}
+	_, _, ok := splitHostPath(addr)
+	return ok
+}
+
+// IsLocal reports whether addr resolves to the local filesystem — a bare
+// path or an explicit "file://" address — as opposed to SFTP or an object
+// store. Callers that need a real local directory, such as fsnotify
+// watching, can use this to skip remote roots instead of checking for a
+// ":" in addr, which s3://, gs://, webdav://, and file:// all contain too.
+func IsLocal(addr string) bool {
+	if idx := strings.Index(addr, "://"); idx != -1 {
+		return addr[:idx] == "file"
+	}
+	_, _, ok := splitHostPath(addr)
+	return !ok
+}
+
+// splitHostPath splits a legacy "host:/path" address into host and path.
+func splitHostPath(addr string) (host, path string, ok bool) {
+	idx := strings.Index(addr, ":")
+	if idx == -1 {
+		return "", "", false
+	}
+	return addr[:idx], strings.TrimRight(addr[idx+1:], "/"), true
+}
+
+// fsAdapter adapts a transport.Remote to FS, renaming Mkdir to MkdirAll and
+// dropping Close (an FS is expected to be short-lived, scoped to one
+// ListFS/sync call rather than held open across a TUI session).
+type fsAdapter struct{ r transport.Remote }
+
+func (a fsAdapter) Walk(root string, fn func(string, transport.FileInfo) error) error {
+	return a.r.Walk(root, fn)
+}
+func (a fsAdapter) Open(path string) (io.ReadCloser, error)          { return a.r.Open(path) }
+func (a fsAdapter) Stat(path string) (transport.FileInfo, error)     { return a.r.Stat(path) }
+func (a fsAdapter) MkdirAll(path string) error                       { return a.r.Mkdir(path) }
+func (a fsAdapter) Create(path string) (io.WriteCloser, error)       { return a.r.Create(path) }
+
+// ListFS walks addr through its registered FS backend and returns hashes
+// for all files, computed with h. Unlike ListLocal it works uniformly
+// regardless of backend, so a DirectoryPair's Destination can be remote
+// too, not just its Source. Local roots go through a persistent
+// per-root hash cache (see CacheContext) so a refresh only re-hashes files
+// that actually changed since the last run.
+func ListFS(addr string, h Hasher) ([]FileHash, error) {
+	fsys, err := OpenFS(addr)
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := fsys.(fsAdapter); ok {
+		if local, ok := a.r.(*transport.Local); ok {
+			return listLocalCached(local.Root, h)
+		}
+	}
+	var results []FileHash
+	err = fsys.Walk(".", func(path string, info transport.FileInfo) error {
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sum := h.New()
+		if _, err := io.Copy(sum, f); err != nil {
+			return err
+		}
+		results = append(results, FileHash{Path: path, Hash: hex.EncodeToString(sum.Sum(nil))})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// localCaches memoizes the CacheContext for each local root by its absolute
+// path, so a long-lived process (the TUI) reuses one in-memory cache across
+// repeated refreshes instead of reloading it from disk every time.
+var (
+	localCachesMu sync.Mutex
+	localCaches   = make(map[string]*CacheContext)
+)
+
+// listLocalCached hashes root through its persistent hash cache, loading
+// the cache on first use and saving it back after every refresh (a no-op
+// once nothing has changed, per CacheContext.Save's dirty check).
+func listLocalCached(root string, h Hasher) ([]FileHash, error) {
+	c, err := cacheForRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.ListLocal(root, h)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Save(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// cacheForRoot returns the CacheContext for root, loading it from
+// ~/.cache/titanic on first use and reusing the same instance thereafter.
+func cacheForRoot(root string) (*CacheContext, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	abs = filepath.Clean(abs)
+
+	localCachesMu.Lock()
+	defer localCachesMu.Unlock()
+	if c, ok := localCaches[abs]; ok {
+		return c, nil
+	}
+	sum := sha256.Sum256([]byte(abs))
+	path, err := CachePath(hex.EncodeToString(sum[:]))
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewCacheContext(path)
+	if err != nil {
+		return nil, err
+	}
+	localCaches[abs] = c
+	return c, nil
+}
+
+// CopyFS copies path from src to dst, creating any missing parent
+// directories at the destination.
+func CopyFS(src, dst FS, path string) error {
+	r, err := src.Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := dst.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
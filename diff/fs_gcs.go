@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	"titanic_app/transport"
+)
+
+func init() {
+	RegisterFS("gs", func(addr string) (FS, error) {
+		bucket, prefix, ok := strings.Cut(addr, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("gs address %q must be bucket[/prefix]", addr)
+		}
+		if !ok {
+			prefix = ""
+		}
+		client, err := storage.NewClient(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("gs: creating client: %w", err)
+		}
+		return &gcsFS{bucket: client.Bucket(bucket), prefix: strings.TrimSuffix(prefix, "/")}, nil
+	})
+}
+
+// gcsFS implements FS over a Google Cloud Storage bucket. Like S3, GCS has
+// no real directories, so MkdirAll is a no-op.
+type gcsFS struct {
+	bucket *storage.BucketHandle
+	prefix string
+}
+
+func (f *gcsFS) key(path string) string {
+	if f.prefix == "" {
+		return path
+	}
+	return f.prefix + "/" + path
+}
+
+func (f *gcsFS) Walk(root string, fn func(path string, info transport.FileInfo) error) error {
+	ctx := context.Background()
+	walkPrefix := f.key(root)
+	if root == "." {
+		walkPrefix = f.key("")
+	}
+	it := f.bucket.Objects(ctx, &storage.Query{Prefix: walkPrefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("gs: listing objects: %w", err)
+		}
+		rel := strings.TrimPrefix(attrs.Name, f.prefix+"/")
+		info := transport.FileInfo{Name: rel, Size: attrs.Size, ModTime: attrs.Updated}
+		if err := fn(rel, info); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *gcsFS) Open(path string) (io.ReadCloser, error) {
+	r, err := f.bucket.Object(f.key(path)).NewReader(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("gs: open %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (f *gcsFS) Stat(path string) (transport.FileInfo, error) {
+	attrs, err := f.bucket.Object(f.key(path)).Attrs(context.Background())
+	if err != nil {
+		return transport.FileInfo{}, fmt.Errorf("gs: stat %s: %w", path, err)
+	}
+	return transport.FileInfo{Name: path, Size: attrs.Size, ModTime: attrs.Updated}, nil
+}
+
+// MkdirAll is a no-op: GCS has no real directories, only object name prefixes.
+func (f *gcsFS) MkdirAll(string) error { return nil }
+
+func (f *gcsFS) Create(path string) (io.WriteCloser, error) {
+	return f.bucket.Object(f.key(path)).NewWriter(context.Background()), nil
+}
@@ -0,0 +1,135 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"titanic_app/transport"
+)
+
+func init() {
+	RegisterFS("s3", func(addr string) (FS, error) {
+		bucket, prefix, ok := strings.Cut(addr, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("s3 address %q must be bucket[/prefix]", addr)
+		}
+		if !ok {
+			prefix = ""
+		}
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("s3: loading AWS config: %w", err)
+		}
+		return &s3FS{client: s3.NewFromConfig(cfg), bucket: bucket, prefix: strings.TrimSuffix(prefix, "/")}, nil
+	})
+}
+
+// s3FS implements FS over an S3-compatible bucket. Object stores have no
+// real directories, so MkdirAll is a no-op and keys are just prefix-joined
+// paths.
+type s3FS struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func (f *s3FS) key(path string) string {
+	if f.prefix == "" {
+		return path
+	}
+	return f.prefix + "/" + path
+}
+
+func (f *s3FS) Walk(root string, fn func(path string, info transport.FileInfo) error) error {
+	ctx := context.Background()
+	walkPrefix := f.key(root)
+	if root == "." {
+		walkPrefix = f.key("")
+	}
+	paginator := s3.NewListObjectsV2Paginator(f.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(f.bucket),
+		Prefix: aws.String(walkPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("s3: listing %s: %w", f.bucket, err)
+		}
+		for _, obj := range page.Contents {
+			rel := strings.TrimPrefix(aws.ToString(obj.Key), f.prefix+"/")
+			info := transport.FileInfo{
+				Name:    rel,
+				Size:    aws.ToInt64(obj.Size),
+				ModTime: aws.ToTime(obj.LastModified),
+			}
+			if err := fn(rel, info); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *s3FS) Open(path string) (io.ReadCloser, error) {
+	out, err := f.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key(path)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3: get %s: %w", path, err)
+	}
+	return out.Body, nil
+}
+
+func (f *s3FS) Stat(path string) (transport.FileInfo, error) {
+	out, err := f.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key(path)),
+	})
+	if err != nil {
+		return transport.FileInfo{}, fmt.Errorf("s3: head %s: %w", path, err)
+	}
+	var modTime time.Time
+	if out.LastModified != nil {
+		modTime = *out.LastModified
+	}
+	return transport.FileInfo{Name: path, Size: aws.ToInt64(out.ContentLength), ModTime: modTime}, nil
+}
+
+// MkdirAll is a no-op: S3 has no real directories, only key prefixes.
+func (f *s3FS) MkdirAll(string) error { return nil }
+
+func (f *s3FS) Create(path string) (io.WriteCloser, error) {
+	return &s3Writer{fs: f, path: path}, nil
+}
+
+// s3Writer buffers writes in memory and uploads them as a single object on
+// Close, since S3 has no native streaming-write API for arbitrary sizes
+// without multipart bookkeeping this module doesn't need yet.
+type s3Writer struct {
+	fs   *s3FS
+	path string
+	buf  bytes.Buffer
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *s3Writer) Close() error {
+	_, err := w.fs.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(w.fs.bucket),
+		Key:    aws.String(w.fs.key(w.path)),
+		Body:   bytes.NewReader(w.buf.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: put %s: %w", w.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package diff_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"titanic_app/diff"
+)
+
+func TestListFSAndCopyFSLocal(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(src, "fileA.txt"), []byte("content-a"), 0644); err != nil {
+		t.Fatalf("write fileA: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatalf("mkdir sub: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "fileB.log"), []byte("content-b"), 0644); err != nil {
+		t.Fatalf("write fileB: %v", err)
+	}
+
+	list, err := diff.ListFS(src, diff.MD5)
+	if err != nil {
+		t.Fatalf("ListFS returned error: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(list))
+	}
+
+	srcFS, err := diff.OpenFS(src)
+	if err != nil {
+		t.Fatalf("OpenFS(src) returned error: %v", err)
+	}
+	dstFS, err := diff.OpenFS(dst)
+	if err != nil {
+		t.Fatalf("OpenFS(dst) returned error: %v", err)
+	}
+	if err := diff.CopyFS(srcFS, dstFS, "fileA.txt"); err != nil {
+		t.Fatalf("CopyFS returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dst, "fileA.txt"))
+	if err != nil {
+		t.Fatalf("reading copied file: %v", err)
+	}
+	if string(got) != "content-a" {
+		t.Errorf("copied content = %q, want %q", got, "content-a")
+	}
+}
+
+func TestOpenFSUnknownScheme(t *testing.T) {
+	if _, err := diff.OpenFS("ftp://example.com/path"); err == nil {
+		t.Fatal("expected error for unregistered scheme, got nil")
+	}
+}
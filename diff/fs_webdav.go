@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/studio-b12/gowebdav"
+
+	"titanic_app/transport"
+)
+
+func init() {
+	RegisterFS("webdav", func(addr string) (FS, error) {
+		if addr == "" {
+			return nil, fmt.Errorf("webdav address must include a host")
+		}
+		client := gowebdav.NewClient("https://"+addr, os.Getenv("WEBDAV_USER"), os.Getenv("WEBDAV_PASSWORD"))
+		return &webdavFS{client: client}, nil
+	})
+}
+
+// webdavFS implements FS over a WebDAV share, authenticating with the
+// WEBDAV_USER/WEBDAV_PASSWORD environment variables the same way the SFTP
+// backend defers to ssh-agent rather than embedding credentials in config.
+type webdavFS struct {
+	client *gowebdav.Client
+}
+
+func (f *webdavFS) Walk(root string, fn func(path string, info transport.FileInfo) error) error {
+	return f.walk(root, root, fn)
+}
+
+func (f *webdavFS) walk(base, dir string, fn func(path string, info transport.FileInfo) error) error {
+	entries, err := f.client.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("webdav: readdir %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		full := path.Join(dir, e.Name())
+		if e.IsDir() {
+			if err := f.walk(base, full, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(full, base), "/")
+		info := transport.FileInfo{Name: e.Name(), Size: e.Size(), ModTime: e.ModTime()}
+		if err := fn(rel, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *webdavFS) Open(path string) (io.ReadCloser, error) {
+	r, err := f.client.ReadStream(path)
+	if err != nil {
+		return nil, fmt.Errorf("webdav: open %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (f *webdavFS) Stat(path string) (transport.FileInfo, error) {
+	info, err := f.client.Stat(path)
+	if err != nil {
+		return transport.FileInfo{}, fmt.Errorf("webdav: stat %s: %w", path, err)
+	}
+	return transport.FileInfo{Name: info.Name(), Size: info.Size(), Mode: info.Mode(), ModTime: info.ModTime(), IsDir: info.IsDir()}, nil
+}
+
+func (f *webdavFS) MkdirAll(path string) error {
+	return f.client.MkdirAll(path, 0755)
+}
+
+func (f *webdavFS) Create(path string) (io.WriteCloser, error) {
+	return &webdavWriter{client: f.client, path: path}, nil
+}
+
+// webdavWriter buffers writes and uploads them on Close, since gowebdav's
+// Write takes a whole io.Reader rather than exposing an incremental stream.
+type webdavWriter struct {
+	client *gowebdav.Client
+	path   string
+	buf    []byte
+}
+
+func (w *webdavWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+func (w *webdavWriter) Close() error {
+	if err := w.client.MkdirAll(path.Dir(w.path), 0755); err != nil {
+		return err
+	}
+	return w.client.Write(w.path, w.buf, 0644)
+}
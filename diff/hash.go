@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"github.com/zeebo/blake3"
+)
+
+// Hasher abstracts the hash algorithm used to fingerprint file contents, so
+// ListLocal and ListFS aren't locked into a single algorithm.
+type Hasher interface {
+	// New returns a fresh hash.Hash instance.
+	New() hash.Hash
+	// Name identifies the algorithm (e.g. "blake3").
+	Name() string
+}
+
+type md5Hasher struct{}
+
+func (md5Hasher) New() hash.Hash { return md5.New() }
+func (md5Hasher) Name() string   { return "md5" }
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) New() hash.Hash { return sha256.New() }
+func (sha256Hasher) Name() string   { return "sha256" }
+
+type blake3Hasher struct{}
+
+func (blake3Hasher) New() hash.Hash { return blake3.New() }
+func (blake3Hasher) Name() string   { return "blake3" }
+
+// MD5, SHA256, and BLAKE3 are the built-in Hasher implementations. BLAKE3 is
+// the default: it's faster than MD5 or SHA-256 and, unlike MD5, isn't
+// collision-prone.
+var (
+	MD5     Hasher = md5Hasher{}
+	SHA256  Hasher = sha256Hasher{}
+	BLAKE3  Hasher = blake3Hasher{}
+	Default Hasher = BLAKE3
+)
+
+// HasherByName resolves a Hasher from a DirectoryPair.Hash config value. An
+// empty name resolves to Default.
+func HasherByName(name string) (Hasher, error) {
+	switch name {
+	case "":
+		return Default, nil
+	case "blake3":
+		return BLAKE3, nil
+	case "md5":
+		return MD5, nil
+	case "sha256":
+		return SHA256, nil
+	default:
+		return nil, fmt.Errorf("unknown hash algorithm %q", name)
+	}
+}
@@ -0,0 +1,86 @@
+package diff
+
+// radixNode is a node in a compressed prefix (radix) tree keyed by path.
+// Each child is keyed by the first byte of its prefix, so a lookup only
+// ever compares the bytes that differ between sibling keys.
+type radixNode struct {
+	prefix   string
+	record   *cacheRecord
+	children map[byte]*radixNode
+}
+
+// newRadixNode creates an empty node rooted at prefix.
+func newRadixNode(prefix string) *radixNode {
+	return &radixNode{prefix: prefix, children: make(map[byte]*radixNode)}
+}
+
+// insert adds or replaces the record stored at key, splitting edges as
+// needed to keep the tree compressed.
+func (n *radixNode) insert(key string, rec *cacheRecord) {
+	if key == "" {
+		n.record = rec
+		return
+	}
+	child, ok := n.children[key[0]]
+	if !ok {
+		n.children[key[0]] = &radixNode{prefix: key, record: rec, children: make(map[byte]*radixNode)}
+		return
+	}
+	cp := commonPrefixLen(child.prefix, key)
+	switch {
+	case cp == len(child.prefix):
+		// child.prefix fully matches; descend with the remaining suffix.
+		child.insert(key[cp:], rec)
+	case cp == len(key):
+		// key is a strict prefix of child.prefix; split child below a new node.
+		split := &radixNode{prefix: key, record: rec, children: make(map[byte]*radixNode)}
+		child.prefix = child.prefix[cp:]
+		split.children[child.prefix[0]] = child
+		n.children[key[0]] = split
+	default:
+		// key and child.prefix diverge partway through; split at the divergence.
+		split := newRadixNode(child.prefix[:cp])
+		child.prefix = child.prefix[cp:]
+		split.children[child.prefix[0]] = child
+		remainder := key[cp:]
+		split.children[remainder[0]] = &radixNode{prefix: remainder, record: rec, children: make(map[byte]*radixNode)}
+		n.children[key[0]] = split
+	}
+}
+
+// get looks up the record stored at key.
+func (n *radixNode) get(key string) (*cacheRecord, bool) {
+	if key == "" {
+		return n.record, n.record != nil
+	}
+	child, ok := n.children[key[0]]
+	if !ok || len(key) < len(child.prefix) || key[:len(child.prefix)] != child.prefix {
+		return nil, false
+	}
+	return child.get(key[len(child.prefix):])
+}
+
+// walk visits every key/record pair in the tree. Order is not guaranteed to
+// be lexical, since children are stored in a map.
+func (n *radixNode) walk(prefix string, fn func(key string, rec *cacheRecord)) {
+	full := prefix + n.prefix
+	if n.record != nil {
+		fn(full, n.record)
+	}
+	for _, child := range n.children {
+		child.walk(full, fn)
+	}
+}
+
+// commonPrefixLen returns the length of the longest common prefix of a and b.
+func commonPrefixLen(a, b string) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher recursively watches a set of local directories for changes and
+// emits a debounced signal on Events() whenever any of them change, so
+// callers can recompute diffs without waiting on a manual refresh.
+type Watcher struct {
+	fsw    *fsnotify.Watcher
+	events chan struct{}
+}
+
+// NewWatcher creates a Watcher, recursively adding watches under each of
+// roots. Events within debounce of one another are collapsed into a single
+// signal.
+func NewWatcher(roots []string, debounce time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{fsw: fsw, events: make(chan struct{}, 1)}
+	for _, root := range roots {
+		if err := w.addRecursive(root); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+	}
+	go w.loop(debounce)
+	return w, nil
+}
+
+// addRecursive adds fsnotify watches for root and every directory beneath it.
+func (w *Watcher) addRecursive(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return w.fsw.Add(path)
+		}
+		return nil
+	})
+}
+
+// loop reads fsnotify events, adding watches for newly created directories
+// and debouncing bursts of events into a single notification on events.
+func (w *Watcher) loop(debounce time.Duration) {
+	var timer *time.Timer
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addRecursive(event.Name)
+				}
+			}
+			if timer == nil {
+				timer = time.AfterFunc(debounce, w.notify)
+			} else {
+				timer.Reset(debounce)
+			}
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) notify() {
+	select {
+	case w.events <- struct{}{}:
+	default:
+	}
+}
+
+// Events returns a channel that receives a value whenever the watched
+// directories change.
+func (w *Watcher) Events() <-chan struct{} { return w.events }
+
+// Close stops the underlying fsnotify watcher.
+func (w *Watcher) Close() error { return w.fsw.Close() }
+
+// PollRemote returns a channel that receives a value every interval. It's
+// the refresh trigger used for a DirectoryPair's remote source or
+// destination, which can't be watched directly with fsnotify.
+func PollRemote(interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch
+}
@@ -1,27 +1,26 @@
 package main
 
 import (
-	"path/filepath"
 	"flag"
-	"bufio"
-	"bytes"
-	"crypto/md5"
-	"encoding/hex"
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
-	"strings"
 
 	"github.com/spf13/viper"
 	bubbletea "github.com/charmbracelet/bubbletea"
+	"titanic_app/diff"
 )
 
-// DirectoryPair represents a source (local or remote) and destination
-// Remote source format: host:/absolute/path/
+// DirectoryPair represents a source and destination, either of which may be
+// local or remote. Both accept either the legacy "host:/absolute/path"
+// SSH form, or a "scheme://..." URL resolved through diff.OpenFS's backend
+// registry (file://, sftp://, s3://, gs://, webdav://); a bare path is
+// treated as local.
 type DirectoryPair struct {
 	Source      string `mapstructure:"source"`
 	Destination string `mapstructure:"destination"`
+	// Hash selects the hash algorithm used to fingerprint files for this
+	// pair: "md5", "sha256", or "blake3" (default).
+	Hash string `mapstructure:"hash"`
 }
 
 // Config holds directory pairs
@@ -29,99 +28,33 @@ type Config struct {
 	DirectoryPairs []DirectoryPair `mapstructure:"directory_pairs"`
 }
 
-// md5Hash computes MD5 for a local file
-func md5Hash(path string) (string, error) {
-	f, err := os.Open(path)
+// fsMap resolves addr through diff.OpenFS and hashes every file under it
+// with the algorithm named by hashName, keyed by path, for the non-TUI
+// comparison path below.
+func fsMap(addr, hashName string) (map[string]string, error) {
+	h, err := diff.HasherByName(hashName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer f.Close()
-	
-	h := md5.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(h.Sum(nil)), nil
-}
-
-// getRemoteMap uses SSH to run md5sum on remote files
-func getRemoteMap(source string) (map[string]string, error) {
-	// parse host and path
-	parts := strings.SplitN(source, ":", 2)
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid remote source %s", source)
-	}
-	host, path := parts[0], parts[1]
-	// ensure no trailing slash in path for cd
-	path = strings.TrimRight(path, "/")
-	// build command
-	cmd := exec.Command("ssh", host, fmt.Sprintf("cd %s && find . -type f -exec md5sum {} +", path))
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("ssh error: %w", err)
+	list, err := diff.ListFS(addr, h)
+	if err != nil {
+		return nil, err
 	}
-
-	m := make(map[string]string)
-	s := bufio.NewScanner(&out)
-	for s.Scan() {
-		fields := strings.Fields(s.Text())
-		if len(fields) < 2 {
-			continue
-		}
-		hash := fields[0]
-		file := fields[1]
-		rel := strings.TrimPrefix(file, "./")
-		m[rel] = hash
+	m := make(map[string]string, len(list))
+	for _, fh := range list {
+		m[fh.Path] = fh.Hash
 	}
 	return m, nil
 }
 
-// listLocalMap uses find+md5sum locally
-func listLocalMap(dir string) (map[string]string, error) {
-	// ensure no trailing slash
-	dir = strings.TrimRight(dir, "/")
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("cd %s && find . -type f -exec md5sum {} +", dir))
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("local find error: %w", err)
-	}
-
-
-	m := make(map[string]string)
-	s := bufio.NewScanner(&out)
-	for s.Scan() {
-		fields := strings.Fields(s.Text())
-		if len(fields) < 2 {
-			continue
-		}
-		hash := fields[0]
-		file := fields[1]
-		rel := strings.TrimPrefix(file, "./")
-		m[rel] = hash
-	}
-	return m, nil
-}
-
-// padColumns formats four columns: status, filename, srcHash, dstHash
 // syncDifferences syncs missing or mismatched files from source to destination
 func syncDifferences(pair DirectoryPair) {
-	// refresh maps
-	srcMap := make(map[string]string)
-	var err error
-	if strings.Contains(pair.Source, ":") {
-		srcMap, err = getRemoteMap(pair.Source)
-	} else {
-		srcMap, err = listLocalMap(pair.Source)
-	}
+	srcMap, err := fsMap(pair.Source, pair.Hash)
 	if err != nil {
 		fmt.Println("Error refreshing source maps:", err)
 		return
 	}
-	dstMap, err := listLocalMap(pair.Destination)
+	dstMap, err := fsMap(pair.Destination, pair.Hash)
 	if err != nil {
 		fmt.Println("Error refreshing destination maps:", err)
 		return
@@ -132,29 +65,27 @@ func syncDifferences(pair DirectoryPair) {
 		if ok && sh == dh {
 			continue
 		}
-		// ensure destination dir exists
-		destPath := filepath.Join(pair.Destination, rel)
-		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
-			fmt.Println("Error creating dest dir:", err)
-			continue
-		}
-		// build rsync command
-		var cmd *exec.Cmd
-		if strings.Contains(pair.Source, ":") {
-			// remote source
-			hostPath := fmt.Sprintf("%s:%s/%s", strings.SplitN(pair.Source, ":", 2)[0], strings.TrimRight(strings.SplitN(pair.Source, ":", 2)[1], "/"), rel)
-			cmd = exec.Command("rsync", "-avz", hostPath, destPath)
-		} else {
-			srcPath := filepath.Join(pair.Source, rel)
-			cmd = exec.Command("rsync", "-avz", srcPath, destPath)
-		}
 		fmt.Println("Syncing", rel)
-		if out, err := cmd.CombinedOutput(); err != nil {
-			fmt.Printf("Error syncing %s: %v\nOutput: %s\n", rel, err, string(out))
+		if err := copyFile(pair, rel); err != nil {
+			fmt.Printf("Error syncing %s: %v\n", rel, err)
 		}
 	}
 }
 
+// copyFile streams rel from pair.Source to pair.Destination through their FS
+// backends, so either side may be local, SFTP, or an object store.
+func copyFile(pair DirectoryPair, rel string) error {
+	src, err := diff.OpenFS(pair.Source)
+	if err != nil {
+		return err
+	}
+	dst, err := diff.OpenFS(pair.Destination)
+	if err != nil {
+		return err
+	}
+	return diff.CopyFS(src, dst, rel)
+}
+
 // padColumns formats four columns: status, filename, srcHash, dstHash
 func padColumns(status, file, src, dst string) string {
 	return fmt.Sprintf("%-15s %-45s %-33s %-33s\n", status, file, src, dst)
@@ -162,21 +93,12 @@ func padColumns(status, file, src, dst string) string {
 
 // highlightDifferences compares source vs destination maps
 func highlightDifferences(pair DirectoryPair) string {
-	
-
-	var srcMap map[string]string
-	var err error
-	if strings.Contains(pair.Source, ":") {
-		srcMap, err = getRemoteMap(pair.Source)
-	} else {
-		srcMap, err = listLocalMap(pair.Source)
-	}
+	srcMap, err := fsMap(pair.Source, pair.Hash)
 	if err != nil {
 		return fmt.Sprintf("Error retrieving source: %v", err)
 	}
 
-	// always local for destination
-	dstMap, err := listLocalMap(pair.Destination)
+	dstMap, err := fsMap(pair.Destination, pair.Hash)
 	if err != nil {
 		return fmt.Sprintf("Error retrieving destination: %v", err)
 	}
@@ -2,17 +2,29 @@ package main
 
 import (
 	"fmt"
-	"os/exec"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"titanic_app/deltasync"
 	"titanic_app/diff"
+	"titanic_app/transport"
 
 	bubbletea "github.com/charmbracelet/bubbletea"
 	tea      "github.com/charmbracelet/bubbletea"
 )
 
+// watchDebounce is how long the filesystem watcher waits for a burst of
+// events to settle before triggering a recompute.
+const watchDebounce = 250 * time.Millisecond
+
+// remotePollInterval is how often a remote source or destination is polled for changes,
+// since it can't be watched directly with fsnotify.
+const remotePollInterval = 5 * time.Second
+
 // Styles for diff rows and messages
 var (
 	matchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#D4EDDA")).Foreground(lipgloss.Color("#155724"))
@@ -43,24 +55,90 @@ type Model struct {
 	Diffs   [][]diff.Diff
 	Loading bool
 	Syncing map[string]struct{}
+	watch   <-chan struct{}
 }
 
-// NewModel initializes Model and precomputes diffs
+// NewModel initializes Model, precomputes diffs, and starts watching every
+// pair's directories for changes.
 func NewModel(cfg Config) Model {
 	m := Model{
 		Pairs:   cfg.DirectoryPairs,
 		Syncing: make(map[string]struct{}),
 	}
 	m.Diffs = computeAllDiffs(m.Pairs)
+	m.watch = startWatching(m.Pairs)
 	return m
 }
 
-// computeAllDiffs runs ListLocal/Remote and ComputeDiff for each pair
+// startWatching sets up a diff.Watcher over every local directory referenced
+// by pairs (each Source and Destination that's local) and a polling
+// fallback for every non-local one, fanning all of their change signals
+// into one channel so the caller never has to press "r" to refresh.
+func startWatching(pairs []DirectoryPair) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case out <- struct{}{}:
+		default:
+		}
+	}
+	pollRemote := func() {
+		go func(ch <-chan struct{}) {
+			for range ch {
+				notify()
+			}
+		}(diff.PollRemote(remotePollInterval))
+	}
+
+	var roots []string
+	for _, p := range pairs {
+		if diff.IsLocal(p.Destination) {
+			roots = append(roots, strings.TrimPrefix(p.Destination, "file://"))
+		} else {
+			pollRemote()
+		}
+		if diff.IsLocal(p.Source) {
+			roots = append(roots, strings.TrimPrefix(p.Source, "file://"))
+		} else {
+			pollRemote()
+		}
+	}
+
+	if len(roots) > 0 {
+		if w, err := diff.NewWatcher(roots, watchDebounce); err == nil {
+			go func() {
+				for range w.Events() {
+					notify()
+				}
+			}()
+		}
+	}
+	return out
+}
+
+// watchCmd blocks until the watcher signals a change, then triggers a diff
+// recompute. Update re-issues this command after every diffsMsg so the
+// watch loop keeps running for the lifetime of the program.
+func watchCmd(ch <-chan struct{}, pairs []DirectoryPair) tea.Cmd {
+	return func() tea.Msg {
+		<-ch
+		return diffsMsg(computeAllDiffs(pairs))
+	}
+}
+
+// computeAllDiffs runs ListFS against both sides of each pair and computes
+// their diff. Source and Destination are treated symmetrically, so either
+// one can be local, SFTP, or an object store.
 func computeAllDiffs(pairs []DirectoryPair) [][]diff.Diff {
 	var all [][]diff.Diff
 	for _, p := range pairs {
-		src, err1 := getFileHashes(p.Source)
-		dst, err2 := diff.ListLocal(p.Destination)
+		h, err := diff.HasherByName(p.Hash)
+		if err != nil {
+			all = append(all, nil)
+			continue
+		}
+		src, err1 := diff.ListFS(p.Source, h)
+		dst, err2 := diff.ListFS(p.Destination, h)
 		if err1 != nil || err2 != nil {
 			all = append(all, nil)
 		} else {
@@ -82,22 +160,25 @@ func syncStartCmd(index int, path string) tea.Cmd {
 	return func() tea.Msg { return syncStartMsg{Index: index, Path: path} }
 }
 
-// syncFileCmd returns a Cmd that performs rsync and returns a syncDoneMsg
-func syncFileCmd(index int, path string, pr DirectoryPair) tea.Cmd {
+// syncFileCmd returns a Cmd that brings path up to date at pr.Destination
+// and returns a syncDoneMsg. A Mismatch from an SFTP source to a local
+// destination is updated in place with deltasync, transferring only the
+// changed bytes; anything else (a MissingDestination file, a local source,
+// a non-local destination, or any other scheme) is copied whole.
+func syncFileCmd(index int, path string, pr DirectoryPair, status diff.DiffStatus) tea.Cmd {
 	return func() tea.Msg {
-		var cmd *exec.Cmd
-		// build source path
-		if strings.Contains(pr.Source, ":") {
-			host := strings.SplitN(pr.Source, ":", 2)[0]
-			hostPath := fmt.Sprintf("%s:%s/%s", host, strings.TrimRight(strings.SplitN(pr.Source, ":", 2)[1], "/"), path)
-			cmd = exec.Command("rsync", "-avz", hostPath, filepath.Join(pr.Destination, path))
+		var err error
+		if status == diff.Mismatch && diff.IsSFTP(pr.Source) && diff.IsLocal(pr.Destination) {
+			err = patchFile(pr, path)
 		} else {
-			srcPath := filepath.Join(pr.Source, path)
-			cmd = exec.Command("rsync", "-avz", srcPath, filepath.Join(pr.Destination, path))
+			err = copyFile(pr, path)
 		}
-		err := cmd.Run()
 		// recalc src hash
-		src, _ := getFileHashes(pr.Source)
+		h, hashErr := diff.HasherByName(pr.Hash)
+		if hashErr != nil {
+			return syncDoneMsg{Index: index, Path: path, Err: hashErr}
+		}
+		src, _ := diff.ListFS(pr.Source, h)
 		var newHash string
 		for _, fh := range src {
 			if fh.Path == path {
@@ -109,8 +190,57 @@ func syncFileCmd(index int, path string, pr DirectoryPair) tea.Cmd {
 	}
 }
 
-// Init does nothing initially (diffs already computed)
-func (m Model) Init() tea.Cmd { return nil }
+// copyFile streams path from pr.Source to pr.Destination through their FS
+// backends, replacing the previous per-file "rsync" subprocess. Because it
+// goes through diff.OpenFS rather than transport.Dial directly, either side
+// can be local, SFTP, or an object store.
+func copyFile(pr DirectoryPair, path string) error {
+	src, err := diff.OpenFS(pr.Source)
+	if err != nil {
+		return err
+	}
+	dst, err := diff.OpenFS(pr.Destination)
+	if err != nil {
+		return err
+	}
+	return diff.CopyFS(src, dst, path)
+}
+
+// patchFile updates path at pr.Destination in place using deltasync's
+// rolling-checksum delta against pr.Source, so only the changed blocks are
+// transferred instead of the whole file.
+func patchFile(pr DirectoryPair, path string) error {
+	src, err := transport.Dial(strings.TrimPrefix(pr.Source, "sftp://"))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	r, err := src.Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	ra, ok := r.(io.ReaderAt)
+	if !ok {
+		return copyFile(pr, path)
+	}
+
+	dst, err := os.OpenFile(filepath.Join(strings.TrimPrefix(pr.Destination, "file://"), path), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	return deltasync.Patch(ra, dst)
+}
+
+// Init starts listening on the watcher set up in NewModel (diffs are already
+// computed, so it has nothing else to do).
+func (m Model) Init() tea.Cmd {
+	if m.watch == nil {
+		return nil
+	}
+	return watchCmd(m.watch, m.Pairs)
+}
 
 // Update handles incoming messages and key events
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -118,6 +248,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case diffsMsg:
 		m.Diffs = [][]diff.Diff(msg)
 		m.Loading = false
+		if m.watch != nil {
+			return m, watchCmd(m.watch, m.Pairs)
+		}
 		return m, nil
 
 	case syncStartMsg:
@@ -165,7 +298,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for _, d := range m.Diffs[idx] {
 				if d.Status == diff.MissingDestination || d.Status == diff.Mismatch {
 					cmds = append(cmds, syncStartCmd(idx, d.Path))
-					cmds = append(cmds, syncFileCmd(idx, d.Path, pair))
+					cmds = append(cmds, syncFileCmd(idx, d.Path, pair, d.Status))
 				}
 			}
 			return m, tea.Batch(cmds...)
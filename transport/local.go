@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Local implements Remote over the local filesystem, rooted at Root.
+type Local struct {
+	Root string
+}
+
+// NewLocal returns a Remote rooted at root.
+func NewLocal(root string) *Local {
+	return &Local{Root: root}
+}
+
+func (l *Local) Walk(root string, fn func(path string, info FileInfo) error) error {
+	base := filepath.Join(l.Root, root)
+	return filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(rel, osFileInfo(info))
+	})
+}
+
+func (l *Local) Open(path string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(l.Root, path))
+}
+
+func (l *Local) Stat(path string) (FileInfo, error) {
+	info, err := os.Stat(filepath.Join(l.Root, path))
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return osFileInfo(info), nil
+}
+
+func (l *Local) Mkdir(path string) error {
+	return os.MkdirAll(filepath.Join(l.Root, path), 0755)
+}
+
+func (l *Local) Create(path string) (io.WriteCloser, error) {
+	full := filepath.Join(l.Root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(full)
+}
+
+func (l *Local) Close() error { return nil }
+
+func osFileInfo(info os.FileInfo) FileInfo {
+	return FileInfo{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"sort"
+)
+
+// mockFile is an in-memory file used by Mock.
+type mockFile struct {
+	data []byte
+	info FileInfo
+}
+
+// Mock is an in-memory Remote implementation for tests that exercise
+// callers of the transport package without a real SSH connection.
+type Mock struct {
+	files map[string]*mockFile
+}
+
+// NewMock returns an empty in-memory Remote.
+func NewMock() *Mock {
+	return &Mock{files: make(map[string]*mockFile)}
+}
+
+// AddFile seeds p with data, as if it had been written by Create.
+func (m *Mock) AddFile(p string, data []byte) {
+	m.files[p] = &mockFile{data: data, info: FileInfo{Name: path.Base(p), Size: int64(len(data))}}
+}
+
+func (m *Mock) Walk(root string, fn func(path string, info FileInfo) error) error {
+	paths := make([]string, 0, len(m.files))
+	for p := range m.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if root != "." && root != "" && !pathHasPrefix(p, root) {
+			continue
+		}
+		if err := fn(p, m.files[p].info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Mock) Open(p string) (io.ReadCloser, error) {
+	f, ok := m.files[p]
+	if !ok {
+		return nil, &fsPathError{op: "open", path: p}
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func (m *Mock) Stat(p string) (FileInfo, error) {
+	f, ok := m.files[p]
+	if !ok {
+		return FileInfo{}, &fsPathError{op: "stat", path: p}
+	}
+	return f.info, nil
+}
+
+func (m *Mock) Mkdir(string) error { return nil }
+
+func (m *Mock) Create(p string) (io.WriteCloser, error) {
+	return &mockWriter{mock: m, path: p}, nil
+}
+
+func (m *Mock) Close() error { return nil }
+
+// mockWriter buffers writes and commits them to the Mock on Close, mirroring
+// how a real sftp.Client write is only durable once the handle is closed.
+type mockWriter struct {
+	mock *Mock
+	path string
+	buf  bytes.Buffer
+}
+
+func (w *mockWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *mockWriter) Close() error {
+	data := append([]byte(nil), w.buf.Bytes()...)
+	w.mock.files[w.path] = &mockFile{data: data, info: FileInfo{Name: path.Base(w.path), Size: int64(len(data))}}
+	return nil
+}
+
+type fsPathError struct {
+	op   string
+	path string
+}
+
+func (e *fsPathError) Error() string { return e.op + " " + e.path + ": no such file" }
+
+func pathHasPrefix(p, root string) bool {
+	return p == root || (len(p) > len(root) && p[:len(root)] == root && p[len(root)] == '/')
+}
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTP implements Remote over a single multiplexed SSH connection to host,
+// replacing the per-file "ssh"/"rsync" subprocesses the module used to spawn.
+type SFTP struct {
+	host   string
+	base   string
+	client *ssh.Client
+	fs     *sftp.Client
+}
+
+// DialSFTP opens one SSH connection to host and wraps it in an SFTP client.
+// Hostname, port, and user are resolved from ~/.ssh/config the same way the
+// ssh binary would, and authentication is attempted via ssh-agent.
+func DialSFTP(host, base string) (*SFTP, error) {
+	cfg, addr, err := sshClientConfig(host)
+	if err != nil {
+		return nil, fmt.Errorf("sftp %s: %w", host, err)
+	}
+	conn, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("ssh dial %s: %w", host, err)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sftp new client for %s: %w", host, err)
+	}
+	return &SFTP{host: host, base: strings.TrimRight(base, "/"), client: conn, fs: client}, nil
+}
+
+func (s *SFTP) full(p string) string {
+	return path.Join(s.base, p)
+}
+
+func (s *SFTP) Walk(root string, fn func(path string, info FileInfo) error) error {
+	base := s.full(root)
+	walker := s.fs.Walk(base)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return err
+		}
+		info := walker.Stat()
+		if info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(base, walker.Path())
+		if err != nil {
+			return err
+		}
+		if err := fn(filepath.ToSlash(rel), sftpFileInfo(info)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SFTP) Open(p string) (io.ReadCloser, error) {
+	return s.fs.Open(s.full(p))
+}
+
+func (s *SFTP) Stat(p string) (FileInfo, error) {
+	info, err := s.fs.Stat(s.full(p))
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return sftpFileInfo(info), nil
+}
+
+func (s *SFTP) Mkdir(p string) error {
+	return s.fs.MkdirAll(s.full(p))
+}
+
+func (s *SFTP) Create(p string) (io.WriteCloser, error) {
+	full := s.full(p)
+	if err := s.fs.MkdirAll(path.Dir(full)); err != nil {
+		return nil, err
+	}
+	return s.fs.Create(full)
+}
+
+func (s *SFTP) Close() error {
+	s.fs.Close()
+	return s.client.Close()
+}
+
+func sftpFileInfo(info os.FileInfo) FileInfo {
+	return FileInfo{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
+
+// sshClientConfig builds an ssh.ClientConfig and dial address for host,
+// resolving aliases from ~/.ssh/config and authenticating via ssh-agent.
+func sshClientConfig(host string) (*ssh.ClientConfig, string, error) {
+	hostname := ssh_config.Get(host, "HostName")
+	if hostname == "" {
+		hostname = host
+	}
+	user := ssh_config.Get(host, "User")
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+	port := ssh_config.Get(host, "Port")
+	if port == "" {
+		port = "22"
+	}
+
+	auth, err := agentAuth()
+	if err != nil {
+		return nil, "", err
+	}
+
+	hostKeyCallback, err := knownHostsCallback()
+	if err != nil {
+		return nil, "", err
+	}
+
+	cfg := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: hostKeyCallback,
+	}
+	return cfg, net.JoinHostPort(hostname, port), nil
+}
+
+// agentAuth returns an AuthMethod backed by a running ssh-agent, as pointed
+// to by SSH_AUTH_SOCK. The module relies on key-based auth via the agent
+// rather than prompting for passwords or parsing private keys itself.
+func agentAuth() (ssh.AuthMethod, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, fmt.Errorf("SSH_AUTH_SOCK not set; start ssh-agent and add a key with ssh-add")
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, fmt.Errorf("dial ssh-agent: %w", err)
+	}
+	return ssh.PublicKeysCallback(agent.NewClient(conn).Signers), nil
+}
+
+// knownHostsCallback verifies host keys against ~/.ssh/known_hosts.
+func knownHostsCallback() (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+}
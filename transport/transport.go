@@ -0,0 +1,62 @@
+// Package transport provides a uniform way to walk, read, and write files on
+// local or remote filesystems. It replaces the previous approach of shelling
+// out to the system's ssh and rsync binaries, streaming everything instead
+// over a single multiplexed SSH connection per remote host.
+package transport
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// FileInfo describes a single file or directory, mirroring the subset of
+// os.FileInfo that callers need without requiring a concrete os.FileInfo.
+type FileInfo struct {
+	Name    string
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+	IsDir   bool
+}
+
+// Remote is a uniform interface over a filesystem, whether local or reached
+// over SFTP, so callers don't need to special-case remote hosts.
+type Remote interface {
+	// Walk calls fn for every regular file under root, with paths relative to root.
+	Walk(root string, fn func(path string, info FileInfo) error) error
+	// Open opens path for reading.
+	Open(path string) (io.ReadCloser, error)
+	// Stat returns file info for path.
+	Stat(path string) (FileInfo, error)
+	// Mkdir creates path and any missing parents.
+	Mkdir(path string) error
+	// Create creates (or truncates) path for writing, creating parent
+	// directories as needed.
+	Create(path string) (io.WriteCloser, error)
+	// Close releases the underlying connection, if any.
+	Close() error
+}
+
+// Dial returns a Remote for addr, which is either a bare local path or a
+// "host:/path" remote address. Remote addresses are dialed once over SFTP;
+// callers should reuse the returned Remote and Close it when done, rather
+// than dialing per file as the previous ssh/rsync commands did.
+func Dial(addr string) (Remote, error) {
+	host, base, ok := splitHostPath(addr)
+	if !ok {
+		return NewLocal(addr), nil
+	}
+	return DialSFTP(host, base)
+}
+
+// splitHostPath splits a "host:/path" address into its host and path. It
+// returns ok=false for plain local paths.
+func splitHostPath(addr string) (host, path string, ok bool) {
+	idx := strings.Index(addr, ":")
+	if idx == -1 {
+		return "", "", false
+	}
+	return addr[:idx], addr[idx+1:], true
+}
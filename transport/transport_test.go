@@ -0,0 +1,104 @@
+package transport_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"titanic_app/transport"
+)
+
+func TestLocalWalkOpenCreate(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "sub"), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "sub", "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	local := transport.NewLocal(tmp)
+
+	var seen []string
+	if err := local.Walk(".", func(path string, info transport.FileInfo) error {
+		seen = append(seen, path)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+	if len(seen) != 1 || filepath.ToSlash(seen[0]) != "sub/a.txt" {
+		t.Fatalf("unexpected walk result: %v", seen)
+	}
+
+	r, err := local.Open(filepath.Join("sub", "a.txt"))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("expected %q, got %q", "hello", string(data))
+	}
+
+	w, err := local.Create(filepath.Join("new", "b.txt"))
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := w.Write([]byte("world")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(tmp, "new", "b.txt"))
+	if err != nil {
+		t.Fatalf("readfile failed: %v", err)
+	}
+	if string(got) != "world" {
+		t.Errorf("expected %q, got %q", "world", string(got))
+	}
+}
+
+func TestMockRoundTrip(t *testing.T) {
+	m := transport.NewMock()
+	m.AddFile("a.txt", []byte("one"))
+
+	w, err := m.Create("b.txt")
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := w.Write([]byte("two")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	var seen []string
+	if err := m.Walk(".", func(path string, info transport.FileInfo) error {
+		seen = append(seen, path)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 files, got %v", seen)
+	}
+
+	r, err := m.Open("b.txt")
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "two" {
+		t.Errorf("expected %q, got %q", "two", string(data))
+	}
+}